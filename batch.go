@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BatchRowError records a single failed row from a batch input file, keeping
+// its 1-based position so users can find and fix it in their source file:
+// the file line number for CSV/TSV input (the header is line 1), or the
+// array element index for JSON input.
+type BatchRowError struct {
+	Line int
+	Err  error
+}
+
+func (e *BatchRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ParseBatchFile reads a CSV, TSV, or JSON file of label rows, keyed by the
+// LabelData field names, and returns the successfully parsed rows alongside
+// a BatchRowError for every row that failed to parse or validate. A partial
+// result is still returned when some rows fail so callers can act on the
+// rows that succeeded.
+func ParseBatchFile(path string) ([]LabelData, []BatchRowError, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return parseBatchJSON(f)
+	case ".tsv":
+		return parseBatchDelimited(f, '\t')
+	default:
+		return parseBatchDelimited(f, ',')
+	}
+}
+
+func parseBatchJSON(r io.Reader) ([]LabelData, []BatchRowError, error) {
+	var raw []LabelData
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var rows []LabelData
+	var rowErrs []BatchRowError
+	for i, data := range raw {
+		if strings.TrimSpace(data.Title) == "" {
+			rowErrs = append(rowErrs, BatchRowError{Line: i + 1, Err: fmt.Errorf("title is required")})
+			continue
+		}
+		rows = append(rows, data)
+	}
+	return rows, rowErrs, nil
+}
+
+func parseBatchDelimited(r io.Reader, delimiter rune) ([]LabelData, []BatchRowError, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.TrimLeadingSpace = true
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+
+	var rows []LabelData
+	var rowErrs []BatchRowError
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rowErrs = append(rowErrs, BatchRowError{Line: line, Err: err})
+			continue
+		}
+
+		data, err := rowToLabelData(headers, record)
+		if err != nil {
+			rowErrs = append(rowErrs, BatchRowError{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, data)
+	}
+	return rows, rowErrs, nil
+}
+
+// rowToLabelData maps a delimited row onto LabelData by matching each header
+// against an exported field name, case-insensitively. String fields (and
+// named string types like BarcodeSymbology) are set directly; numeric
+// fields (BarcodeModuleWidth, QRSizePixels) are parsed, and a malformed
+// value is reported as a row error rather than silently dropped. Unknown
+// headers are ignored so extra bookkeeping columns don't cause a failure.
+func rowToLabelData(headers, record []string) (LabelData, error) {
+	var data LabelData
+	v := reflect.ValueOf(&data).Elem()
+	for i, header := range headers {
+		if i >= len(record) {
+			continue
+		}
+		name := strings.TrimSpace(header)
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+		field := v.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return data, fmt.Errorf("column %q: %w", name, err)
+			}
+			field.SetFloat(f)
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return data, fmt.Errorf("column %q: %w", name, err)
+			}
+			field.SetInt(int64(n))
+		}
+	}
+
+	if strings.TrimSpace(data.Title) == "" {
+		return data, fmt.Errorf("title is required")
+	}
+	return data, nil
+}
+
+// GenerateBatch renders one page per row into a single multi-page PDF,
+// writing the result to out. Rows must already be validated; use
+// ParseBatchFile (or filter manually) to drop bad rows before calling this.
+// If onProgress is non-nil, it's called after each row is rendered with the
+// 1-based row index and the total row count.
+func (lg *LabelGenerator) GenerateBatch(rows []LabelData, out io.Writer, onProgress func(done, total int)) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("no rows to generate")
+	}
+
+	pdf := lg.createPDF()
+	for i, data := range rows {
+		if strings.TrimSpace(data.Title) == "" {
+			return fmt.Errorf("row %d: title is required", i+1)
+		}
+		if err := lg.RenderLabel(pdf, lg.template, data); err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if onProgress != nil {
+			onProgress(i+1, len(rows))
+		}
+	}
+
+	return pdf.Output(out)
+}
+
+// generateBatchFilename mirrors generateFilename's naming convention for the
+// combined multi-page batch output.
+func (lg *LabelGenerator) generateBatchFilename() string {
+	return "labels_batch.pdf"
+}
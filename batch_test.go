@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRowToLabelDataMapsHeadersCaseInsensitively(t *testing.T) {
+	headers := []string{"title", "SKU", "Barcode"}
+	record := []string{"Cordless Drill", "SKU-123", "012345678905"}
+
+	data, err := rowToLabelData(headers, record)
+	if err != nil {
+		t.Fatalf("rowToLabelData() error = %v", err)
+	}
+	if data.Title != "Cordless Drill" {
+		t.Errorf("Title = %q, want %q", data.Title, "Cordless Drill")
+	}
+	if data.SKU != "SKU-123" {
+		t.Errorf("SKU = %q, want %q", data.SKU, "SKU-123")
+	}
+	if data.Barcode != "012345678905" {
+		t.Errorf("Barcode = %q, want %q", data.Barcode, "012345678905")
+	}
+}
+
+func TestRowToLabelDataIgnoresUnknownHeaders(t *testing.T) {
+	headers := []string{"Title", "Warehouse Bin"}
+	record := []string{"Cordless Drill", "A12"}
+
+	data, err := rowToLabelData(headers, record)
+	if err != nil {
+		t.Fatalf("rowToLabelData() error = %v", err)
+	}
+	if data.Title != "Cordless Drill" {
+		t.Errorf("Title = %q, want %q", data.Title, "Cordless Drill")
+	}
+}
+
+func TestRowToLabelDataRequiresTitle(t *testing.T) {
+	headers := []string{"Title", "SKU"}
+	record := []string{"", "SKU-123"}
+
+	if _, err := rowToLabelData(headers, record); err == nil {
+		t.Error("rowToLabelData() with a blank title should error, got nil")
+	}
+}
+
+func TestRowToLabelDataParsesNumericFields(t *testing.T) {
+	headers := []string{"Title", "BarcodeModuleWidth", "QRSizePixels"}
+	record := []string{"Cordless Drill", "0.02", "512"}
+
+	data, err := rowToLabelData(headers, record)
+	if err != nil {
+		t.Fatalf("rowToLabelData() error = %v", err)
+	}
+	if data.BarcodeModuleWidth != 0.02 {
+		t.Errorf("BarcodeModuleWidth = %v, want 0.02", data.BarcodeModuleWidth)
+	}
+	if data.QRSizePixels != 512 {
+		t.Errorf("QRSizePixels = %v, want 512", data.QRSizePixels)
+	}
+}
+
+func TestRowToLabelDataRejectsMalformedNumericField(t *testing.T) {
+	headers := []string{"Title", "QRSizePixels"}
+	record := []string{"Cordless Drill", "not-a-number"}
+
+	if _, err := rowToLabelData(headers, record); err == nil {
+		t.Error("rowToLabelData() with a malformed QRSizePixels should error, got nil")
+	}
+}
+
+func TestParseBatchDelimitedSkipsBadRowsButKeepsGoodOnes(t *testing.T) {
+	csv := "Title,SKU\nCordless Drill,SKU-1\n,SKU-2\nImpact Driver,SKU-3\n"
+
+	rows, rowErrs, err := parseBatchDelimited(strings.NewReader(csv), ',')
+	if err != nil {
+		t.Fatalf("parseBatchDelimited() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+	if len(rowErrs) != 1 {
+		t.Fatalf("len(rowErrs) = %d, want 1", len(rowErrs))
+	}
+	if rowErrs[0].Line != 3 {
+		t.Errorf("rowErrs[0].Line = %d, want 3", rowErrs[0].Line)
+	}
+}
+
+func TestParseBatchJSONSkipsBadRowsButKeepsGoodOnes(t *testing.T) {
+	body := `[{"Title":"Cordless Drill","SKU":"SKU-1"},{"SKU":"SKU-2"}]`
+
+	rows, rowErrs, err := parseBatchJSON(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseBatchJSON() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("len(rows) = %d, want 1", len(rows))
+	}
+	if len(rowErrs) != 1 {
+		t.Fatalf("len(rowErrs) = %d, want 1", len(rowErrs))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+// builtinTemplates are the label stocks this tool ships support for out of
+// the box. Each is a single label cell sized to its physical media; sheet
+// stocks like Avery 5160 are rendered one label per page (matching batch
+// mode's one-page-per-row output) rather than tiled 30-up onto one page.
+var builtinTemplates = map[string]Template{
+	"4x6":            template4x6,
+	"avery5160":      templateAvery5160,
+	"dymo30252":      templateDymo30252,
+	"brother-dk1201": templateBrotherDK1201,
+}
+
+const defaultTemplateName = "4x6"
+
+// template4x6 reproduces this tool's original hard-coded 4"x6" landscape
+// equipment-tag layout as a zone template.
+var template4x6 = Template{
+	Name:         "4x6",
+	WidthInches:  pageHeightInches, // 6in wide once landscape-oriented
+	HeightInches: pageWidthInches,  // 4in tall
+	Orientation:  "L",
+	MarginInches: marginInches,
+	Border:       true,
+	Zones: []Zone{
+		{Name: "title", X: 0.125, Y: 0.225, W: 5.75, H: 0.3, FontSize: titleFontSize, Bold: true, Align: "C"},
+		{Name: "description", X: 0.125, Y: 0.75, W: 5.65, H: 2.125, FontSize: descFontSize, Align: "L"},
+		{Name: "checkoutDate", X: 0.125, Y: 2.575, W: 3.0, H: 0.3, FontSize: priceFontSize, Bold: true, Align: "L"},
+		{Name: "returnLocation", X: 0.125, Y: 2.875, W: 3.0, H: 0.3, FontSize: priceFontSize, Bold: true, Align: "L"},
+		{Name: "barcode", X: 3.75, Y: 2.9, W: 2.1, H: 0.35, FontSize: barcodeFontSize},
+		{Name: "sku", X: 0.125, Y: 3.275, W: 5.75, H: 0.3, FontSize: skuFontSize, Bold: true, Align: "C"},
+		{Name: "returnDate", X: 3.0, Y: 3.275, W: 2.875, H: 0.3, FontSize: priceFontSize, Bold: true, Align: "R"},
+		{Name: "qr", X: 5.375, Y: 3.375, W: 0.5, H: 0.5},
+	},
+}
+
+// templateAvery5160 targets a single cell of an Avery 5160 sheet
+// (1in x 2.625in address labels, 30 per sheet).
+var templateAvery5160 = Template{
+	Name:         "avery5160",
+	WidthInches:  2.625,
+	HeightInches: 1.0,
+	Orientation:  "L",
+	MarginInches: 0.06,
+	Zones: []Zone{
+		{Name: "title", X: 0.06, Y: 0.08, W: 2.505, H: 0.3, FontSize: 11, Bold: true, Align: "C"},
+		{Name: "sku", X: 0.06, Y: 0.42, W: 2.505, H: 0.22, FontSize: 7, Align: "C"},
+		{Name: "barcode", X: 0.06, Y: 0.64, W: 2.505, H: 0.3, FontSize: 6},
+	},
+}
+
+// templateDymo30252 targets a Dymo 30252 standard address label
+// (1.125in x 3.5in).
+var templateDymo30252 = Template{
+	Name:         "dymo30252",
+	WidthInches:  3.5,
+	HeightInches: 1.125,
+	Orientation:  "L",
+	MarginInches: 0.08,
+	Zones: []Zone{
+		{Name: "title", X: 0.08, Y: 0.1, W: 3.34, H: 0.35, FontSize: 14, Bold: true, Align: "C"},
+		{Name: "sku", X: 0.08, Y: 0.5, W: 3.34, H: 0.25, FontSize: 8, Align: "C"},
+		{Name: "barcode", X: 0.08, Y: 0.78, W: 3.34, H: 0.3, FontSize: 6},
+	},
+}
+
+// templateBrotherDK1201 targets a Brother DK-1201 standard address label
+// (29mm x 90mm, ~1.14in x 3.54in).
+var templateBrotherDK1201 = Template{
+	Name:         "brother-dk1201",
+	WidthInches:  3.54,
+	HeightInches: 1.14,
+	Orientation:  "L",
+	MarginInches: 0.08,
+	Zones: []Zone{
+		{Name: "title", X: 0.08, Y: 0.1, W: 3.38, H: 0.35, FontSize: 14, Bold: true, Align: "C"},
+		{Name: "sku", X: 0.08, Y: 0.5, W: 3.38, H: 0.25, FontSize: 8, Align: "C"},
+		{Name: "barcode", X: 0.08, Y: 0.79, W: 3.38, H: 0.3, FontSize: 6},
+	},
+}
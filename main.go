@@ -1,18 +1,25 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
 	"github.com/jung-kurt/gofpdf"
-	"github.com/skip2/go-qrcode"
 )
 
 const (
@@ -26,6 +33,49 @@ const (
 	priceFontSize   = 14
 	skuFontSize     = 10
 	barcodeFontSize = 8
+
+	// barcodeDPI controls the pixel density the symbology is rasterized at
+	// before being placed on the page; 300dpi keeps narrow-bar Code128 scans
+	// reliable on typical thermal printers.
+	barcodeDPI                = 300
+	defaultBarcodeModuleWidth = 0.013 // inches per narrow bar/module
+
+	// qrDPI controls the pixel density QR codes are encoded at before being
+	// scaled onto the page; 300dpi keeps a 0.5in QR zone scannable on
+	// thermal media. defaultQRSizePixels is the source bitmap size at that
+	// density for a half-inch-square zone like template4x6's.
+	qrDPI               = 300
+	defaultQRZoneInches = 0.5 // template4x6's "qr" zone is 0.5in square
+	defaultQRSizePixels = int(defaultQRZoneInches * qrDPI)
+
+	batchPreviewRows = 3
+)
+
+// BarcodeSymbology identifies which 1-D symbology a barcode should be
+// rendered with. The zero value triggers auto-detection in
+// resolveBarcodeSymbology. Only Code128 and EAN-13 are supported here; QR
+// codes are a separate zone driven by LabelData.URL1 and the QRErrorCorrection/
+// QRSizePixels fields, not a BarcodeSymbology value.
+type BarcodeSymbology string
+
+const (
+	BarcodeSymbologyAuto    BarcodeSymbology = ""
+	BarcodeSymbologyCode128 BarcodeSymbology = "code128"
+	BarcodeSymbologyEAN13   BarcodeSymbology = "ean13"
+)
+
+var ean13Pattern = regexp.MustCompile(`^\d{13}$`)
+
+// QRErrorCorrection identifies the Reed-Solomon error-correction level a QR
+// code is encoded with. The zero value uses go-qrcode's Medium level.
+type QRErrorCorrection string
+
+const (
+	QRErrorCorrectionAuto    QRErrorCorrection = ""
+	QRErrorCorrectionLow     QRErrorCorrection = "low"
+	QRErrorCorrectionMedium  QRErrorCorrection = "medium"
+	QRErrorCorrectionHigh    QRErrorCorrection = "high"
+	QRErrorCorrectionHighest QRErrorCorrection = "highest"
 )
 
 type LabelData struct {
@@ -39,20 +89,54 @@ type LabelData struct {
 	URL1           string
 	URL2           string
 	URL3           string
+
+	// BarcodeSymbology selects the 1-D symbology used for Barcode; leave
+	// unset to auto-detect EAN-13 for 13-digit numeric values and fall
+	// back to Code128 for everything else. QR codes are not a valid value
+	// here; they're rendered from URL1 via the separate "qr" zone.
+	BarcodeSymbology BarcodeSymbology
+	// BarcodeModuleWidth overrides the width, in inches, of the narrowest
+	// bar/module. Zero uses defaultBarcodeModuleWidth.
+	BarcodeModuleWidth float64
+
+	// QRErrorCorrection selects the Reed-Solomon error-correction level for
+	// the URL1 QR code. Leave unset to use go-qrcode's Medium level.
+	QRErrorCorrection QRErrorCorrection
+	// QRSizePixels overrides the source bitmap size, in pixels, the QR code
+	// is encoded at before being scaled onto the page. Zero uses
+	// defaultQRSizePixels, which keeps a 0.5in zone scannable at qrDPI.
+	QRSizePixels int
 }
 
 type LabelGenerator struct {
 	window      fyne.Window
 	statusLabel *widget.Label
+	progressBar *widget.ProgressBar
+	template    Template
+
+	// qrImageSeq gives each registered QR image a unique gofpdf image name,
+	// since batch mode renders many rows (each with its own QR bitmap) into
+	// a single *gofpdf.Fpdf whose image registry is keyed by name.
+	qrImageSeq int
 }
 
-func NewLabelGenerator(window fyne.Window, statusLabel *widget.Label) *LabelGenerator {
+func NewLabelGenerator(window fyne.Window, statusLabel *widget.Label, progressBar *widget.ProgressBar) *LabelGenerator {
 	return &LabelGenerator{
 		window:      window,
 		statusLabel: statusLabel,
+		progressBar: progressBar,
+		template:    builtinTemplates[defaultTemplateName],
 	}
 }
 
+// SetTemplate switches the layout used by generatePDF and GenerateBatch.
+// PrintLabel does not go through the zone engine: it rasters a fixed
+// title/SKU/barcode layout directly at the printer's native resolution, so
+// it ignores the selected template.
+func (lg *LabelGenerator) SetTemplate(tmpl Template) {
+	lg.template = tmpl
+}
+
 func (lg *LabelGenerator) generatePDF(data LabelData) error {
 	if strings.TrimSpace(data.Title) == "" {
 		return fmt.Errorf("title is required")
@@ -60,7 +144,9 @@ func (lg *LabelGenerator) generatePDF(data LabelData) error {
 
 	pdf := lg.createPDF()
 
-	lg.layoutPDF(pdf, data)
+	if err := lg.RenderLabel(pdf, lg.template, data); err != nil {
+		return fmt.Errorf("failed to lay out label: %w", err)
+	}
 
 	filename := lg.generateFilename(data.SKU)
 	if err := pdf.OutputFileAndClose(filename); err != nil {
@@ -73,194 +159,163 @@ func (lg *LabelGenerator) generatePDF(data LabelData) error {
 	return nil
 }
 
-func (lg *LabelGenerator) createPDF() *gofpdf.Fpdf {
-	return gofpdf.NewCustom(&gofpdf.InitType{
-		OrientationStr: "L",
-		UnitStr:        "in",
-		SizeStr:        "Custom",
-		Size: gofpdf.SizeType{
-			Wd: pageWidthInches,
-			Ht: pageHeightInches,
-		},
-	})
-}
-
-func (lg *LabelGenerator) layoutPDF(pdf *gofpdf.Fpdf, data LabelData) {
-	pdf.AddPage()
-
-	pdf.SetAutoPageBreak(false, marginInches) // Prevent automatic page breaks
-
-	contentWidth := pageHeightInches - (2 * marginInches) // Height is used as we are in landscape
-	contentHeight := pageWidthInches - (2 * marginInches) // Width is used as we are in landscape
-
-	// Draw title
-	lg.drawTitle(pdf, data.Title, contentWidth)
+// openBatchDialog lets the user pick a CSV/TSV/JSON file of label rows and
+// previews the parsed result before any PDF is written.
+func (lg *LabelGenerator) openBatchDialog() {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, lg.window)
+			return
+		}
+		if reader == nil {
+			return // user cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		rows, rowErrs, err := ParseBatchFile(path)
+		if err != nil {
+			lg.statusLabel.SetText(fmt.Sprintf("Error: %s", err.Error()))
+			dialog.ShowError(err, lg.window)
+			return
+		}
+		if len(rows) == 0 {
+			err := fmt.Errorf("no valid rows found in %s", filepath.Base(path))
+			dialog.ShowError(err, lg.window)
+			return
+		}
 
-	// Draw description
-	lg.drawDescription(pdf, data.Description, contentWidth)
+		lg.confirmBatch(rows, rowErrs)
+	}, lg.window)
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".tsv", ".json"}))
+	fileDialog.Show()
+}
 
-	// Draw bottom information
-	lg.drawBottomInfo(pdf, data, contentWidth)
+// confirmBatch shows a dry-run preview of the first few rows and asks the
+// user to confirm before the multi-page PDF is generated.
+func (lg *LabelGenerator) confirmBatch(rows []LabelData, rowErrs []BatchRowError) {
+	summary := fmt.Sprintf("%d labels ready", len(rows))
+	if len(rowErrs) > 0 {
+		summary += fmt.Sprintf(", %d rows skipped (see below)", len(rowErrs))
+	}
 
-	// Draw border
-	lg.drawBorder(pdf, contentWidth, contentHeight)
+	content := container.NewVBox(
+		widget.NewLabel(summary),
+		lg.buildBatchPreview(rows),
+	)
+	if len(rowErrs) > 0 {
+		content.Add(widget.NewLabel(formatRowErrors(rowErrs)))
+	}
 
+	dialog.ShowCustomConfirm("Batch Preview", "Generate PDF", "Cancel", content, func(confirm bool) {
+		if confirm {
+			lg.runBatch(rows, rowErrs)
+		}
+	}, lg.window)
 }
 
-func (lg *LabelGenerator) drawTitle(pdf *gofpdf.Fpdf, title string, contentWidth float64) {
-	pdf.SetFont(fontFamily, "B", titleFontSize)
-	words := strings.Fields(title)
-	for i, word := range words {
-		if len(word) > 0 {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-		}
+// buildBatchPreview renders the first batchPreviewRows rows as a scrollable
+// strip of images so users can sanity-check a batch before committing to it.
+func (lg *LabelGenerator) buildBatchPreview(rows []LabelData) fyne.CanvasObject {
+	n := batchPreviewRows
+	if len(rows) < n {
+		n = len(rows)
 	}
-	title = strings.Join(words, " ")
-
-	// Center the title using CellFormat with alignStr
-	pdf.SetXY(marginInches, marginInches+0.1)
-	pdf.CellFormat(contentWidth, 0.3, title, "0", 0, "C", false, 0, "")
-}
 
-func (lg *LabelGenerator) drawDescription(pdf *gofpdf.Fpdf, description string, contentWidth float64) {
-	pdf.SetFont(fontFamily, "", descFontSize)
-	pdf.SetXY(marginInches, marginInches+0.625)
-
-	words := strings.Fields(description)
-	line := ""
-	yPos := marginInches + 0.625
-	maxWidth := contentWidth - 0.1
-	maxY := pageHeightInches - marginInches - 1.0
-
-	for _, word := range words {
-		testLine := line
-		if line != "" {
-			testLine += " " + word
-		} else {
-			testLine = word
-		}
+	images := container.NewHBox()
+	for _, data := range rows[:n] {
+		img := canvas.NewImageFromImage(renderPreviewImage(data))
+		img.FillMode = canvas.ImageFillOriginal
+		images.Add(img)
+	}
 
-		if pdf.GetStringWidth(testLine) > maxWidth {
-			if line != "" {
-				pdf.SetXY(marginInches, yPos)
-				pdf.CellFormat(pdf.GetStringWidth(line), 0.2, line, "0", 0, "L", false, 0, "")
-				yPos += 0.25
-				line = word
-			} else {
-				line = lg.truncateWord(pdf, word, maxWidth)
-			}
-		} else {
-			line = testLine
-		}
+	scroll := container.NewHScroll(images)
+	scroll.SetMinSize(fyne.NewSize(400, previewHeightPx+20))
+	return scroll
+}
 
-		// Check if we're about to exceed the available space
-		if yPos > maxY {
-			break // Stop adding more lines to prevent page overflow
-		}
+// runBatch writes the multi-page batch PDF and reports per-row errors that
+// were skipped during parsing.
+func (lg *LabelGenerator) runBatch(rows []LabelData, rowErrs []BatchRowError) {
+	lg.progressBar.SetValue(0)
+	lg.progressBar.Show()
+
+	filename := lg.generateBatchFilename()
+	f, err := os.Create(filename)
+	if err != nil {
+		lg.progressBar.Hide()
+		err = fmt.Errorf("failed to create %s: %w", filename, err)
+		lg.statusLabel.SetText(fmt.Sprintf("Error: %s", err.Error()))
+		dialog.ShowError(err, lg.window)
+		return
 	}
+	defer f.Close()
 
-	// Write the last line if there's space
-	if line != "" && yPos <= maxY {
-		pdf.SetXY(marginInches, yPos)
-		pdf.CellFormat(pdf.GetStringWidth(line), 0.2, line, "0", 0, "L", false, 0, "")
+	onProgress := func(done, total int) {
+		lg.progressBar.SetValue(float64(done) / float64(total))
+		lg.statusLabel.SetText(fmt.Sprintf("Rendering label %d of %d...", done, total))
+	}
+	if err := lg.GenerateBatch(rows, f, onProgress); err != nil {
+		lg.progressBar.Hide()
+		lg.statusLabel.SetText(fmt.Sprintf("Error: %s", err.Error()))
+		dialog.ShowError(err, lg.window)
+		return
 	}
-}
 
-func (lg *LabelGenerator) truncateWord(pdf *gofpdf.Fpdf, word string, maxWidth float64) string {
-	line := word
-	for len(line) > 0 && pdf.GetStringWidth(line) > maxWidth {
-		line = line[:len(line)-1]
+	lg.progressBar.Hide()
+	absPath, _ := filepath.Abs(filename)
+	summary := fmt.Sprintf("Batch PDF saved: %s (%d labels", absPath, len(rows))
+	if len(rowErrs) > 0 {
+		summary += fmt.Sprintf(", %d rows skipped", len(rowErrs))
 	}
-	return line
+	summary += ")"
+	lg.statusLabel.SetText(summary)
+	dialog.ShowInformation("Batch complete", summary, lg.window)
 }
 
-func (lg *LabelGenerator) generateQRCode(url string, filename string) error {
-	if strings.TrimSpace(url) == "" {
-		return nil // Skip empty URLs
+func formatRowErrors(rowErrs []BatchRowError) string {
+	lines := make([]string, len(rowErrs))
+	for i, e := range rowErrs {
+		lines[i] = e.Error()
 	}
-
-	// Generate QR code
-	err := qrcode.WriteFile(url, qrcode.Medium, 256, filename)
-	return err
+	return strings.Join(lines, "\n")
 }
 
-func (lg *LabelGenerator) drawBottomInfo(pdf *gofpdf.Fpdf, data LabelData, contentWidth float64) {
-	bottomY := pageWidthInches - marginInches - 0.6
-
-	// Barcode (above bottom row, right aligned)
-	pdf.SetFont(fontFamily, "B", barcodeFontSize+1)
-	barcodeText := "BC: " + data.Barcode
-	pdf.SetXY(pageWidthInches - marginInches - pdf.GetStringWidth(barcodeText), bottomY-0.35)
-	pdf.CellFormat(pdf.GetStringWidth(barcodeText), 0.3, barcodeText, "0", 0, "R", false, 0, "")
-
-	// SKU (bottom center)
-	pdf.SetFont(fontFamily, "B", skuFontSize+1)
-	skuText := "SKU: " + data.SKU
-	pdf.SetXY(marginInches, bottomY)
-	pdf.CellFormat(contentWidth, 0.3, skuText, "0", 0, "C", false, 0, "")
-
-	// Return Date (bottom right)
-	pdf.SetFont(fontFamily, "B", priceFontSize)
-	returnText := "Return By: " + data.ReturnDate
-	pdf.SetXY(pageWidthInches - marginInches - pdf.GetStringWidth(returnText), bottomY)
-	pdf.CellFormat(pdf.GetStringWidth(returnText), 0.3, returnText, "0", 0, "R", false, 0, "")
-
-	// Add QR codes for URLs
-	lg.drawQRCodes(pdf, data)
+func (lg *LabelGenerator) createPDF() *gofpdf.Fpdf {
+	return gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "L",
+		UnitStr:        "in",
+		SizeStr:        "Custom",
+		Size: gofpdf.SizeType{
+			Wd: pageWidthInches,
+			Ht: pageHeightInches,
+		},
+	})
 }
 
-func (lg *LabelGenerator) drawQRCodes(pdf *gofpdf.Fpdf, data LabelData) {
-	// QR code size in inches
-	qrSize := 0.5
-
-	// Generate and add QR code (only URL1)
-	url := data.URL1
-	label := "Finalize Restock"
-	qrY := pageWidthInches - marginInches - qrSize // Position QR code at bottom
-
-	// Add Borrowed date above Return to
-	pdf.SetFont(fontFamily, "B", priceFontSize)
-	borrowedY := qrY - 0.8 // Position above Return to
-	pdf.SetXY(marginInches, borrowedY)
-	borrowedText := "Borrowed: " + data.CheckoutDate
-	pdf.CellFormat(pdf.GetStringWidth(borrowedText), 0.3, borrowedText, "0", 0, "L", false, 0, "")
-
-	// Add Return Location above QR code
-	pdf.SetFont(fontFamily, "B", priceFontSize)
-	returnY := qrY - 0.5 // Position above QR code
-	pdf.SetXY(marginInches, returnY)
-	returnText := "Return To: " + data.ReturnLocation
-	pdf.CellFormat(pdf.GetStringWidth(returnText), 0.3, returnText, "0", 0, "L", false, 0, "")
-
-	if strings.TrimSpace(url) != "" {
-		// Generate QR code file
-		qrFilename := "qr_1.png"
-		if err := lg.generateQRCode(url, qrFilename); err == nil {
-			// Position QR code at bottom right (where QR3 was)
-			qrX := pageHeightInches - marginInches - qrSize
-
-			// Add label above QR code
-			pdf.SetFont(fontFamily, "B", 8)
-			labelWidth := pdf.GetStringWidth(label)
-			labelX := qrX + (qrSize-labelWidth)/2 // Center label over QR code
-			labelY := qrY - 0.15                  // Position label above QR code
-			pdf.SetXY(labelX, labelY)
-			pdf.CellFormat(labelWidth, 0.1, label, "0", 0, "C", false, 0, "")
-
-			// Add QR code to PDF
-			pdf.SetXY(qrX, qrY)
-			pdf.Image(qrFilename, qrX, qrY, qrSize, qrSize, false, "", 0, "")
-
-			// Clean up temporary file
-			os.Remove(qrFilename)
-		}
+// resolveBarcodeSymbology returns the symbology to encode data.Barcode with,
+// auto-detecting EAN-13 for 13-digit numeric values and defaulting to
+// Code128 otherwise.
+func resolveBarcodeSymbology(data LabelData) BarcodeSymbology {
+	if data.BarcodeSymbology != BarcodeSymbologyAuto {
+		return data.BarcodeSymbology
+	}
+	if ean13Pattern.MatchString(strings.TrimSpace(data.Barcode)) {
+		return BarcodeSymbologyEAN13
 	}
+	return BarcodeSymbologyCode128
 }
 
-func (lg *LabelGenerator) drawBorder(pdf *gofpdf.Fpdf, contentWidth, contentHeight float64) {
-	pdf.SetLineWidth(0.01)
-	// Border should match the content area where text is drawn
-	pdf.Rect(marginInches, marginInches, contentWidth, contentHeight, "D")
+// encodeBarcode builds the 1-D symbol for data.Barcode, falling back to
+// Code128 if EAN-13 encoding fails (e.g. a bad checksum digit).
+func encodeBarcode(data LabelData) (barcode.Barcode, error) {
+	symbology := resolveBarcodeSymbology(data)
+	if symbology == BarcodeSymbologyEAN13 {
+		if bc, err := ean.Encode(strings.TrimSpace(data.Barcode)); err == nil {
+			return bc, nil
+		}
+	}
+	return code128.Encode(data.Barcode)
 }
 
 func (lg *LabelGenerator) generateFilename(sku string) string {
@@ -268,9 +323,48 @@ func (lg *LabelGenerator) generateFilename(sku string) string {
 	return fmt.Sprintf("label_%s.pdf", safeSKU)
 }
 
+// templateConfigDir is where users can drop their own .json/.yaml template
+// files to make them available alongside the built-in label stocks.
+func templateConfigDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "label-printing", "templates")
+}
+
+// availableTemplates merges the built-in label stocks with any user
+// templates found in templateConfigDir, keyed and sorted by name.
+func availableTemplates() (map[string]Template, []string) {
+	templates := map[string]Template{}
+	for name, tmpl := range builtinTemplates {
+		templates[name] = tmpl
+	}
+
+	if dir := templateConfigDir(); dir != "" {
+		if userTemplates, err := LoadTemplateDir(dir); err == nil {
+			for name, tmpl := range userTemplates {
+				templates[name] = tmpl
+			}
+		}
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return templates, names
+}
+
 func main() {
+	templateFlag := flag.String("template", defaultTemplateName, "label template to start with (built-in name or one from the template config directory)")
+	flag.Parse()
+
+	templates, templateNames := availableTemplates()
+
 	a := app.New()
-	w := a.NewWindow("Label Printer - 4\"x6\" PDF Generator")
+	w := a.NewWindow("Label Printer - PDF Label Generator")
 	w.Resize(fyne.NewSize(500, 700))
 
 	// Create form fields
@@ -298,10 +392,26 @@ func main() {
 	url1Entry := widget.NewEntry()
 	url1Entry.SetText("https://example.com/product1")
 
+	printTargetEntry := widget.NewEntry()
+	printTargetEntry.SetText("/dev/usb/lp0")
+
 	statusLabel := widget.NewLabel("Ready to generate PDF")
 
+	progressBar := widget.NewProgressBar()
+	progressBar.Hide()
+
 	// Create label generator
-	generator := NewLabelGenerator(w, statusLabel)
+	generator := NewLabelGenerator(w, statusLabel, progressBar)
+	if tmpl, ok := templates[*templateFlag]; ok {
+		generator.SetTemplate(tmpl)
+	}
+
+	templateSelect := widget.NewSelect(templateNames, func(name string) {
+		if tmpl, ok := templates[name]; ok {
+			generator.SetTemplate(tmpl)
+		}
+	})
+	templateSelect.Selected = generator.template.Name
 
 	// Button handlers
 	clearFields := func() {
@@ -316,8 +426,8 @@ func main() {
 		statusLabel.SetText("Fields cleared")
 	}
 
-	generatePDF := func() {
-		data := LabelData{
+	formData := func() LabelData {
+		return LabelData{
 			Title:          titleEntry.Text,
 			Description:    descEntry.Text,
 			ReturnLocation: returnLocationEntry.Text,
@@ -329,8 +439,17 @@ func main() {
 			URL2:           "",
 			URL3:           "",
 		}
+	}
+
+	generatePDF := func() {
+		if err := generator.generatePDF(formData()); err != nil {
+			statusLabel.SetText(fmt.Sprintf("Error: %s", err.Error()))
+			dialog.ShowError(err, w)
+		}
+	}
 
-		if err := generator.generatePDF(data); err != nil {
+	printLabel := func() {
+		if err := generator.printLabel(formData(), printTargetEntry.Text); err != nil {
 			statusLabel.SetText(fmt.Sprintf("Error: %s", err.Error()))
 			dialog.ShowError(err, w)
 		}
@@ -338,6 +457,8 @@ func main() {
 
 	// Layout
 	form := container.NewVBox(
+		widget.NewLabel("Label Template:"),
+		templateSelect,
 		widget.NewLabel("Title:"),
 		titleEntry,
 		widget.NewLabel("Description:"),
@@ -354,11 +475,16 @@ func main() {
 		returnDateEntry,
 		widget.NewLabel("URL (QR Code):"),
 		url1Entry,
+		widget.NewLabel("Printer Target (path or host:port):"),
+		printTargetEntry,
 		container.NewHBox(
 			widget.NewButton("Generate PDF", generatePDF),
+			widget.NewButton("Print", printLabel),
 			widget.NewButton("Clear Fields", clearFields),
+			widget.NewButton("Batch Generate...", generator.openBatchDialog),
 		),
 		statusLabel,
+		progressBar,
 	)
 
 	w.SetContent(form)
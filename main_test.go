@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestResolveBarcodeSymbologyAutoDetectsEAN13(t *testing.T) {
+	data := LabelData{Barcode: "0123456789012"}
+	if got := resolveBarcodeSymbology(data); got != BarcodeSymbologyEAN13 {
+		t.Errorf("resolveBarcodeSymbology() = %q, want %q", got, BarcodeSymbologyEAN13)
+	}
+}
+
+func TestResolveBarcodeSymbologyFallsBackToCode128(t *testing.T) {
+	data := LabelData{Barcode: "ASSET-00192"}
+	if got := resolveBarcodeSymbology(data); got != BarcodeSymbologyCode128 {
+		t.Errorf("resolveBarcodeSymbology() = %q, want %q", got, BarcodeSymbologyCode128)
+	}
+}
+
+func TestResolveBarcodeSymbologyHonorsExplicitChoice(t *testing.T) {
+	data := LabelData{Barcode: "0123456789012", BarcodeSymbology: BarcodeSymbologyCode128}
+	if got := resolveBarcodeSymbology(data); got != BarcodeSymbologyCode128 {
+		t.Errorf("resolveBarcodeSymbology() = %q, want %q", got, BarcodeSymbologyCode128)
+	}
+}
+
+func TestEncodeBarcodeCode128(t *testing.T) {
+	data := LabelData{Barcode: "ASSET-00192"}
+	bc, err := encodeBarcode(data)
+	if err != nil {
+		t.Fatalf("encodeBarcode() error = %v", err)
+	}
+	if bc.Bounds().Dx() == 0 {
+		t.Error("encodeBarcode() produced a zero-width symbol")
+	}
+}
+
+func TestEncodeBarcodeEAN13FallsBackToCode128OnBadChecksum(t *testing.T) {
+	data := LabelData{Barcode: "0123456789990"} // wrong check digit (should be 9)
+	bc, err := encodeBarcode(data)
+	if err != nil {
+		t.Fatalf("encodeBarcode() error = %v", err)
+	}
+	if bc.Bounds().Dx() == 0 {
+		t.Error("encodeBarcode() produced a zero-width symbol")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	previewWidthPx  = 240
+	previewHeightPx = 160 // landscape 4x6 aspect, scaled down
+
+	previewBarcodeWidthPx  = 160
+	previewBarcodeHeightPx = 30
+)
+
+// renderPreviewImage draws a simplified rasterized stand-in for a label's
+// PDF layout (border, title, SKU, barcode) so batch mode can show a dry-run
+// preview without invoking a PDF rasterizer.
+func renderPreviewImage(data LabelData) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, previewWidthPx, previewHeightPx))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	drawPreviewBorder(img)
+
+	drawRasterText(img, 8, 20, strings.ToUpper(data.Title))
+	drawRasterText(img, 8, previewHeightPx-40, "SKU: "+data.SKU)
+
+	barcodeY := previewHeightPx - 34
+	if bc := rasterizeBarcode(data, previewBarcodeWidthPx, previewBarcodeHeightPx); bc != nil {
+		dstRect := image.Rect(8, barcodeY, 8+previewBarcodeWidthPx, barcodeY+previewBarcodeHeightPx)
+		draw.Draw(img, dstRect, bc, image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+func drawPreviewBorder(img *image.RGBA) {
+	bounds := img.Bounds()
+	black := color.Black
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		img.Set(x, bounds.Min.Y, black)
+		img.Set(x, bounds.Max.Y-1, black)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		img.Set(bounds.Min.X, y, black)
+		img.Set(bounds.Max.X-1, y, black)
+	}
+}
+
+// drawRasterText draws text onto img with the left end of its baseline at
+// (x, y), so the glyphs sit above y,
+// shared by the batch preview and the direct-to-printer renderer.
+func drawRasterText(img *image.RGBA, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.Black),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+	}
+	d.DrawString(text)
+}
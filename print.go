@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/nbovee/label-printing/printer"
+)
+
+// printMedia is the Brother QL media profile PrintLabel targets; its 4x6in
+// die-cut footprint matches the PDF layout's page size.
+var printMedia = printer.QL1100FourBySix
+
+// PrintLabel renders data directly to a Brother QL raster command stream on
+// target, bypassing PDF generation. target can be an *os.File opened against
+// a USB printer node (e.g. /dev/usb/lp0), a net.Conn dialed to port 9100, or
+// a CUPS raw-queue pipe.
+func PrintLabel(data LabelData, target io.Writer) error {
+	if strings.TrimSpace(data.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+
+	img := renderLabelRaster(data)
+	if err := printer.WriteRaster(target, img, printMedia); err != nil {
+		return fmt.Errorf("failed to print label: %w", err)
+	}
+	return nil
+}
+
+// printBarcodeHeightPins is the height of the rasterized barcode strip drawn
+// by renderLabelRaster, tall enough to stay scannable off the printed label.
+// Its width is computed in renderLabelRaster as printMedia.WidthPins minus
+// margins, so it always runs nearly the full width of printMedia.
+const (
+	printBarcodeHeightPins = 80
+)
+
+// renderLabelRaster draws the label's content at the printer's native pin
+// resolution. It mirrors the PDF layout (title, SKU, scannable barcode)
+// rather than reusing gofpdf, since there's no PDF rasterizer in this
+// dependency chain.
+func renderLabelRaster(data LabelData) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, printMedia.WidthPins, printMedia.LengthPins))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	drawRasterText(img, 24, 80, strings.ToUpper(data.Title))
+	drawRasterText(img, 24, printMedia.LengthPins-120, "SKU: "+data.SKU)
+
+	barcodeWidth := printMedia.WidthPins - 48
+	barcodeY := printMedia.LengthPins - 100
+	if bc := rasterizeBarcode(data, barcodeWidth, printBarcodeHeightPins); bc != nil {
+		dstRect := image.Rect(24, barcodeY, 24+barcodeWidth, barcodeY+printBarcodeHeightPins)
+		draw.Draw(img, dstRect, bc, image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+// openPrintTarget opens target as a printer sink: a host:port address dials
+// a raw TCP socket (e.g. port 9100), anything else is treated as a device
+// or pipe path such as /dev/usb/lp0 or a CUPS raw queue.
+func openPrintTarget(target string) (io.WriteCloser, error) {
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		conn, err := net.Dial("tcp", target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+		}
+		return conn, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	return f, nil
+}
+
+// printLabel opens target and prints data to it, reporting status to
+// statusLabel the same way generatePDF does.
+func (lg *LabelGenerator) printLabel(data LabelData, target string) error {
+	w, err := openPrintTarget(target)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := PrintLabel(data, w); err != nil {
+		return err
+	}
+
+	lg.statusLabel.SetText(fmt.Sprintf("Printed to %s", target))
+	return nil
+}
@@ -0,0 +1,204 @@
+// Package printer implements the Brother QL-series raster command protocol,
+// letting callers drive a thermal label printer directly over USB, a raw
+// TCP socket on port 9100, or a CUPS raw queue, without going through a PDF
+// viewer or driver.
+package printer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// pinsPerByte is fixed by the protocol: each raster byte packs 8 horizontal
+// pins, MSB first.
+const pinsPerByte = 8
+
+// Media describes a Brother QL media profile.
+type Media struct {
+	// Kind is the protocol's media-kind byte: mediaKindContinuous or
+	// mediaKindDieCut.
+	Kind byte
+	// WidthPins is the printable width in pins (e.g. 720 pins for 4in-wide
+	// stock on a QL-1100, an effective ~180dpi across the printable area).
+	WidthPins int
+	// WidthMM and LengthMM are the physical media dimensions in
+	// millimeters, as reported to the printer in the print information
+	// command. LengthMM is 0 for continuous tape.
+	WidthMM, LengthMM int
+	// LengthPins is the expected raster-line count for die-cut labels; the
+	// raster stream is padded with blank lines up to this count so the
+	// printer doesn't error on a short page. Continuous tape media leaves
+	// this at 0 (no padding).
+	LengthPins int
+}
+
+const (
+	mediaKindContinuous byte = 0x0A
+	mediaKindDieCut     byte = 0x0B
+)
+
+// QL1100FourBySix is the 4in x 6in die-cut label profile, matching the
+// 4x6 PDF layout's page size: 720 pins (90 bytes) per raster line and 1109
+// raster lines, roughly 180dpi across the label rather than the QL-1100's
+// full 300dpi.
+var QL1100FourBySix = Media{
+	Kind:       mediaKindDieCut,
+	WidthPins:  720,
+	WidthMM:    102,
+	LengthMM:   152,
+	LengthPins: 1109,
+}
+
+func bytesPerLine(m Media) int {
+	return m.WidthPins / pinsPerByte
+}
+
+// WriteRaster renders img as a Brother QL raster command stream to w. img is
+// thresholded to 1-bit (dark pixels print) using a simple luminance
+// midpoint; callers needing dithering should pre-process img before calling
+// WriteRaster.
+func WriteRaster(w io.Writer, img image.Image, media Media) error {
+	lines, err := rasterLines(img, media)
+	if err != nil {
+		return err
+	}
+
+	cmds := [][]byte{
+		cmdInit(),
+		cmdRasterModeSelect(),
+		cmdPrintInformation(media, len(lines)),
+		cmdAutoCut(true),
+		cmdCutAtEnd(true),
+		cmdMargin(0),
+	}
+	for _, cmd := range cmds {
+		if _, err := w.Write(cmd); err != nil {
+			return fmt.Errorf("failed to write printer command: %w", err)
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := w.Write(cmdRasterLine(line)); err != nil {
+			return fmt.Errorf("failed to write raster line: %w", err)
+		}
+	}
+
+	if _, err := w.Write(cmdPrintWithFeed()); err != nil {
+		return fmt.Errorf("failed to write print command: %w", err)
+	}
+	return nil
+}
+
+// rasterLines packs img into one []byte per raster row, MSB-first, padding
+// die-cut media with blank rows up to media.LengthPins.
+func rasterLines(img image.Image, media Media) ([][]byte, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() > media.WidthPins {
+		return nil, fmt.Errorf("image width %dpx exceeds media width %dpx", bounds.Dx(), media.WidthPins)
+	}
+
+	rowBytes := bytesPerLine(media)
+	lines := make([][]byte, 0, bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		lines = append(lines, packRow(img, y, bounds, rowBytes))
+	}
+
+	if media.LengthPins > 0 {
+		for len(lines) < media.LengthPins {
+			lines = append(lines, make([]byte, rowBytes))
+		}
+		lines = lines[:media.LengthPins]
+	}
+
+	return lines, nil
+}
+
+func packRow(img image.Image, y int, bounds image.Rectangle, rowBytes int) []byte {
+	row := make([]byte, rowBytes)
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		if !isDark(img.At(x, y)) {
+			continue
+		}
+		col := x - bounds.Min.X
+		byteIdx := col / pinsPerByte
+		if byteIdx >= rowBytes {
+			continue
+		}
+		bitIdx := uint(pinsPerByte-1) - uint(col%pinsPerByte)
+		row[byteIdx] |= 1 << bitIdx
+	}
+	return row
+}
+
+func isDark(c color.Color) bool {
+	gray := color.GrayModel.Convert(c).(color.Gray)
+	return gray.Y < 128
+}
+
+// cmdInit is ESC @, resetting the printer's internal state.
+func cmdInit() []byte {
+	return []byte{0x1B, 0x40}
+}
+
+// cmdRasterModeSelect is ESC i a 01, switching the printer into raster mode.
+func cmdRasterModeSelect() []byte {
+	return []byte{0x1B, 0x69, 0x61, 0x01}
+}
+
+// cmdPrintInformation is ESC i z, describing the media kind, width, length,
+// and the number of raster lines about to be sent.
+func cmdPrintInformation(media Media, rasterLineCount int) []byte {
+	const validFlags = 0x02 | 0x04 | 0x08 | 0x40 // kind | width | length | quality
+	cmd := []byte{0x1B, 0x69, 0x7A, validFlags, media.Kind, byte(media.WidthMM), byte(media.LengthMM)}
+	cmd = append(cmd, uint32LE(uint32(rasterLineCount))...)
+	cmd = append(cmd, 0x00, 0x00) // starting page, fixed
+	return cmd
+}
+
+// cmdAutoCut is ESC i M, toggling the printer's automatic cutter.
+func cmdAutoCut(enabled bool) []byte {
+	var flag byte
+	if enabled {
+		flag = 0x40
+	}
+	return []byte{0x1B, 0x69, 0x4D, flag}
+}
+
+// cmdCutAtEnd is ESC i K, requesting a final cut after the last label.
+func cmdCutAtEnd(enabled bool) []byte {
+	var flag byte
+	if enabled {
+		flag = 0x08
+	}
+	return []byte{0x1B, 0x69, 0x4B, flag}
+}
+
+// cmdMargin is ESC i d, setting the feed amount (in dots) before/after the
+// page.
+func cmdMargin(dots int) []byte {
+	cmd := []byte{0x1B, 0x69, 0x64}
+	return append(cmd, uint16LE(uint16(dots))...)
+}
+
+// cmdRasterLine is "g 0x00 <n>" followed by the packed pixel bytes for one
+// raster row.
+func cmdRasterLine(line []byte) []byte {
+	cmd := []byte{0x67, 0x00, byte(len(line))}
+	return append(cmd, line...)
+}
+
+// cmdPrintWithFeed is the 0x1A control code that prints the buffered page
+// and feeds/cuts according to the flags set above.
+func cmdPrintWithFeed() []byte {
+	return []byte{0x1A}
+}
+
+func uint32LE(v uint32) []byte {
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+}
+
+func uint16LE(v uint16) []byte {
+	return []byte{byte(v), byte(v >> 8)}
+}
@@ -0,0 +1,84 @@
+package printer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPackRowSetsBitForDarkPixels(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 16, 1))
+	for x := 0; x < 16; x++ {
+		img.Set(x, 0, color.White)
+	}
+	img.Set(0, 0, color.Black)
+	img.Set(9, 0, color.Black)
+
+	row := packRow(img, 0, img.Bounds(), 2)
+
+	want := []byte{0x80, 0x40}
+	if row[0] != want[0] || row[1] != want[1] {
+		t.Errorf("packRow() = %08b %08b, want %08b %08b", row[0], row[1], want[0], want[1])
+	}
+}
+
+func TestPackRowIgnoresLightPixels(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 1))
+	for x := 0; x < 8; x++ {
+		img.Set(x, 0, color.White)
+	}
+
+	row := packRow(img, 0, img.Bounds(), 1)
+
+	if row[0] != 0 {
+		t.Errorf("packRow() = %08b, want all-clear byte", row[0])
+	}
+}
+
+func TestRasterLinesRejectsOversizedImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 800, 10))
+	media := Media{WidthPins: 720}
+
+	if _, err := rasterLines(img, media); err == nil {
+		t.Error("rasterLines() with an over-width image should error, got nil")
+	}
+}
+
+func TestRasterLinesPadsToLengthPins(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 720, 10))
+	media := Media{WidthPins: 720, LengthPins: 100}
+
+	lines, err := rasterLines(img, media)
+	if err != nil {
+		t.Fatalf("rasterLines() error = %v", err)
+	}
+	if len(lines) != media.LengthPins {
+		t.Errorf("len(lines) = %d, want %d", len(lines), media.LengthPins)
+	}
+	for i, line := range lines {
+		if len(line) != bytesPerLine(media) {
+			t.Fatalf("line %d has %d bytes, want %d", i, len(line), bytesPerLine(media))
+		}
+	}
+	for i := 10; i < media.LengthPins; i++ {
+		for _, b := range lines[i] {
+			if b != 0 {
+				t.Errorf("padding line %d is not blank: %v", i, lines[i])
+				break
+			}
+		}
+	}
+}
+
+func TestRasterLinesNoPaddingForContinuousMedia(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 720, 10))
+	media := Media{WidthPins: 720} // LengthPins == 0: continuous tape
+
+	lines, err := rasterLines(img, media)
+	if err != nil {
+		t.Fatalf("rasterLines() error = %v", err)
+	}
+	if len(lines) != 10 {
+		t.Errorf("len(lines) = %d, want 10 (no padding)", len(lines))
+	}
+}
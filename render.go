@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/jung-kurt/gofpdf"
+	gofpdfbarcode "github.com/jung-kurt/gofpdf/contrib/barcode"
+	"github.com/skip2/go-qrcode"
+)
+
+// qrLabel is the fixed caption drawn above the URL1 QR code; the QR zone
+// always represents the restock/return-processing link, never an arbitrary
+// user-supplied label.
+const qrLabel = "Finalize Restock"
+
+// RenderLabel draws data onto a new page of pdf using tmpl's zones.
+func (lg *LabelGenerator) RenderLabel(pdf *gofpdf.Fpdf, tmpl Template, data LabelData) error {
+	orientation := tmpl.Orientation
+	if orientation == "" {
+		orientation = "L"
+	}
+
+	// gofpdf's beginpage swaps Wd/Ht itself for "L" orientation (f.w =
+	// size.Ht, f.h = size.Wd), unconditionally, not just on a mismatch. So a
+	// landscape template (WidthInches >= HeightInches) must be passed in
+	// swapped terms here to end up with the documented final page size.
+	size := gofpdf.SizeType{Wd: tmpl.WidthInches, Ht: tmpl.HeightInches}
+	if strings.EqualFold(orientation, "L") {
+		size = gofpdf.SizeType{Wd: tmpl.HeightInches, Ht: tmpl.WidthInches}
+	}
+	pdf.AddPageFormat(orientation, size)
+	pdf.SetAutoPageBreak(false, tmpl.MarginInches)
+
+	for _, zone := range tmpl.Zones {
+		if err := lg.renderZone(pdf, zone, data); err != nil {
+			return fmt.Errorf("zone %q: %w", zone.Name, err)
+		}
+	}
+
+	if tmpl.Border {
+		pdf.SetLineWidth(0.01)
+		pdf.Rect(tmpl.MarginInches, tmpl.MarginInches,
+			tmpl.WidthInches-2*tmpl.MarginInches, tmpl.HeightInches-2*tmpl.MarginInches, "D")
+	}
+	return nil
+}
+
+// renderZone dispatches a single zone to its drawing routine by name.
+// Zone names with no corresponding LabelData field are presentational and
+// silently ignored, so a template can carry decorative zones.
+func (lg *LabelGenerator) renderZone(pdf *gofpdf.Fpdf, zone Zone, data LabelData) error {
+	switch zone.Name {
+	case "title":
+		return lg.renderTitleZone(pdf, zone, data.Title)
+	case "description":
+		return lg.renderWrappedTextZone(pdf, zone, data.Description)
+	case "sku":
+		return lg.renderTextZone(pdf, zone, "SKU: "+data.SKU)
+	case "returnLocation":
+		return lg.renderTextZone(pdf, zone, "Return To: "+data.ReturnLocation)
+	case "checkoutDate":
+		return lg.renderTextZone(pdf, zone, "Borrowed: "+data.CheckoutDate)
+	case "returnDate":
+		return lg.renderTextZone(pdf, zone, "Return By: "+data.ReturnDate)
+	case "barcode":
+		return lg.renderBarcodeZone(pdf, zone, data)
+	case "qr":
+		return lg.renderQRZone(pdf, zone, data, qrLabel)
+	default:
+		return nil
+	}
+}
+
+// applyZoneFont sets the active font from a zone's styling, falling back to
+// this tool's defaults for any field the zone leaves unset.
+func (lg *LabelGenerator) applyZoneFont(pdf *gofpdf.Fpdf, zone Zone) {
+	family := zone.Font
+	if family == "" {
+		family = fontFamily
+	}
+	size := zone.FontSize
+	if size == 0 {
+		size = descFontSize
+	}
+	style := ""
+	if zone.Bold {
+		style = "B"
+	}
+	pdf.SetFont(family, style, size)
+}
+
+// zoneAlign returns zone.Align, defaulting to left.
+func zoneAlign(zone Zone) string {
+	if zone.Align == "" {
+		return "L"
+	}
+	return zone.Align
+}
+
+// withZoneRotation runs draw with pdf rotated zone.Rotation degrees about
+// the zone's center, if set.
+func withZoneRotation(pdf *gofpdf.Fpdf, zone Zone, draw func()) {
+	if zone.Rotation == 0 {
+		draw()
+		return
+	}
+	pdf.TransformBegin()
+	defer pdf.TransformEnd()
+	pdf.TransformRotate(zone.Rotation, zone.X+zone.W/2, zone.Y+zone.H/2)
+	draw()
+}
+
+func (lg *LabelGenerator) renderTitleZone(pdf *gofpdf.Fpdf, zone Zone, title string) error {
+	words := strings.Fields(title)
+	for i, word := range words {
+		if len(word) > 0 {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+	}
+	return lg.renderTextZone(pdf, zone, strings.Join(words, " "))
+}
+
+func (lg *LabelGenerator) renderTextZone(pdf *gofpdf.Fpdf, zone Zone, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	lg.applyZoneFont(pdf, zone)
+	withZoneRotation(pdf, zone, func() {
+		pdf.SetXY(zone.X, zone.Y)
+		pdf.CellFormat(zone.W, zone.H, text, "0", 0, zoneAlign(zone), false, 0, "")
+	})
+	return nil
+}
+
+// renderWrappedTextZone word-wraps text within zone's width, stopping once
+// it runs out of the zone's height.
+func (lg *LabelGenerator) renderWrappedTextZone(pdf *gofpdf.Fpdf, zone Zone, text string) error {
+	lg.applyZoneFont(pdf, zone)
+
+	const lineHeight = 0.2
+	const lineGap = 0.05
+
+	words := strings.Fields(text)
+	line := ""
+	yPos := zone.Y
+	maxY := zone.Y + zone.H
+
+	flush := func(content string) {
+		pdf.SetXY(zone.X, yPos)
+		pdf.CellFormat(pdf.GetStringWidth(content), lineHeight, content, "0", 0, "L", false, 0, "")
+	}
+
+	for _, word := range words {
+		testLine := word
+		if line != "" {
+			testLine = line + " " + word
+		}
+
+		if pdf.GetStringWidth(testLine) > zone.W {
+			if line != "" {
+				flush(line)
+				yPos += lineHeight + lineGap
+				line = word
+			} else {
+				line = lg.truncateWord(pdf, word, zone.W)
+			}
+		} else {
+			line = testLine
+		}
+
+		if yPos > maxY {
+			return nil
+		}
+	}
+
+	if line != "" && yPos <= maxY {
+		flush(line)
+	}
+	return nil
+}
+
+func (lg *LabelGenerator) truncateWord(pdf *gofpdf.Fpdf, word string, maxWidth float64) string {
+	line := word
+	for len(line) > 0 && pdf.GetStringWidth(line) > maxWidth {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// renderBarcodeZone draws data.Barcode's scannable symbol within zone,
+// reserving a thin strip at the bottom of the zone for the numeric caption.
+func (lg *LabelGenerator) renderBarcodeZone(pdf *gofpdf.Fpdf, zone Zone, data LabelData) error {
+	if strings.TrimSpace(data.Barcode) == "" {
+		return nil
+	}
+
+	bc, err := encodeBarcode(data)
+	if err != nil {
+		return nil
+	}
+
+	moduleWidth := data.BarcodeModuleWidth
+	if moduleWidth <= 0 {
+		moduleWidth = defaultBarcodeModuleWidth
+	}
+
+	widthInches := moduleWidth * float64(bc.Bounds().Dx())
+	if widthInches > zone.W {
+		widthInches = zone.W
+	}
+
+	const captionHeight = 0.12
+	barHeight := zone.H - captionHeight
+	if barHeight <= 0 {
+		barHeight = zone.H
+	}
+
+	scaled, err := barcode.Scale(bc, int(widthInches*barcodeDPI), int(barHeight*barcodeDPI))
+	if err != nil {
+		return nil
+	}
+
+	x := zone.X + (zone.W-widthInches)/2
+	code := gofpdfbarcode.Register(scaled)
+	gofpdfbarcode.Barcode(pdf, code, x, zone.Y, widthInches, barHeight, false)
+
+	if barHeight < zone.H {
+		pdf.SetFont(fontFamily, "", barcodeFontSize)
+		caption := data.Barcode
+		pdf.SetXY(x+(widthInches-pdf.GetStringWidth(caption))/2, zone.Y+barHeight+0.02)
+		pdf.CellFormat(pdf.GetStringWidth(caption), captionHeight, caption, "0", 0, "L", false, 0, "")
+	}
+	return nil
+}
+
+// rasterizeBarcode encodes data.Barcode's scannable symbol and scales it to
+// widthPx x heightPx, for callers that draw directly onto an image.RGBA
+// (the batch preview and direct-to-printer renderers) rather than a PDF.
+// It returns nil if there's no barcode to draw or it fails to encode.
+func rasterizeBarcode(data LabelData, widthPx, heightPx int) image.Image {
+	if strings.TrimSpace(data.Barcode) == "" {
+		return nil
+	}
+	bc, err := encodeBarcode(data)
+	if err != nil {
+		return nil
+	}
+	scaled, err := barcode.Scale(bc, widthPx, heightPx)
+	if err != nil {
+		return nil
+	}
+	return scaled
+}
+
+// resolveQRRecoveryLevel maps data.QRErrorCorrection onto a go-qrcode
+// recovery level, defaulting to Medium.
+func resolveQRRecoveryLevel(data LabelData) qrcode.RecoveryLevel {
+	switch data.QRErrorCorrection {
+	case QRErrorCorrectionLow:
+		return qrcode.Low
+	case QRErrorCorrectionHigh:
+		return qrcode.High
+	case QRErrorCorrectionHighest:
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
+}
+
+// resolveQRSizePixels returns the source bitmap size to encode a QR code at,
+// falling back to defaultQRSizePixels.
+func resolveQRSizePixels(data LabelData) int {
+	if data.QRSizePixels > 0 {
+		return data.QRSizePixels
+	}
+	return defaultQRSizePixels
+}
+
+// renderQRZone draws a QR code for data.URL1 within zone, with label
+// centered just above it. The code is encoded directly to an in-memory PNG
+// and registered with gofpdf via a reader, rather than going through a
+// temp file on disk, so batch generation can run concurrently and work in
+// read-only sandboxes.
+func (lg *LabelGenerator) renderQRZone(pdf *gofpdf.Fpdf, zone Zone, data LabelData, label string) error {
+	url := data.URL1
+	if strings.TrimSpace(url) == "" {
+		return nil
+	}
+
+	png, err := qrcode.Encode(url, resolveQRRecoveryLevel(data), resolveQRSizePixels(data))
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	lg.qrImageSeq++
+	imageName := fmt.Sprintf("qr-%d", lg.qrImageSeq)
+	imageOpts := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader(imageName, imageOpts, bytes.NewReader(png))
+
+	if label != "" {
+		pdf.SetFont(fontFamily, "B", 8)
+		labelWidth := pdf.GetStringWidth(label)
+		pdf.SetXY(zone.X+(zone.W-labelWidth)/2, zone.Y-0.15)
+		pdf.CellFormat(labelWidth, 0.1, label, "0", 0, "C", false, 0, "")
+	}
+
+	pdf.ImageOptions(imageName, zone.X, zone.Y, zone.W, zone.H, false, imageOpts, 0, "")
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// TestRenderLabelPageSizeMatchesTemplate guards against AddPageFormat being
+// called with unswapped Wd/Ht for a landscape template: gofpdf's beginpage
+// swaps Wd/Ht itself for "L" orientation, so passing a landscape template's
+// dimensions through unmodified silently rotates the page (and clips the
+// barcode/qr zones, which are positioned against the intended wide canvas).
+func TestRenderLabelPageSizeMatchesTemplate(t *testing.T) {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: template4x6.Orientation,
+		UnitStr:        "in",
+		SizeStr:        "Custom",
+		Size:           gofpdf.SizeType{Wd: template4x6.WidthInches, Ht: template4x6.HeightInches},
+	})
+
+	lg := &LabelGenerator{}
+	data := LabelData{Title: "Widget", SKU: "ASSET-001", Barcode: "ASSET-001"}
+	if err := lg.RenderLabel(pdf, template4x6, data); err != nil {
+		t.Fatalf("RenderLabel() error = %v", err)
+	}
+
+	gotWd, gotHt, _ := pdf.PageSize(pdf.PageNo())
+	if gotWd != template4x6.WidthInches || gotHt != template4x6.HeightInches {
+		t.Errorf("PageSize() = (%.3f, %.3f), want (%.3f, %.3f)",
+			gotWd, gotHt, template4x6.WidthInches, template4x6.HeightInches)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Zone is a single named, positioned region of a label template. Zones are
+// addressed by Name when the renderer maps LabelData onto the page; unknown
+// names are ignored so a template can also carry presentational zones with
+// no corresponding data field.
+type Zone struct {
+	Name     string  `json:"name" yaml:"name"`
+	X        float64 `json:"x" yaml:"x"`
+	Y        float64 `json:"y" yaml:"y"`
+	W        float64 `json:"w" yaml:"w"`
+	H        float64 `json:"h" yaml:"h"`
+	Font     string  `json:"font,omitempty" yaml:"font,omitempty"`
+	FontSize float64 `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`
+	Bold     bool    `json:"bold,omitempty" yaml:"bold,omitempty"`
+	// Align is a gofpdf CellFormat alignStr: "L", "C", or "R".
+	Align string `json:"align,omitempty" yaml:"align,omitempty"`
+	// Rotation is in degrees, counter-clockwise about the zone's center.
+	Rotation float64 `json:"rotation,omitempty" yaml:"rotation,omitempty"`
+}
+
+// Template is a JSON/YAML-loadable label layout: a page size plus a set of
+// named zones. Built-in templates live in builtin_templates.go; user
+// templates are loaded from a config directory at runtime via
+// LoadTemplateDir.
+type Template struct {
+	Name         string  `json:"name" yaml:"name"`
+	WidthInches  float64 `json:"widthInches" yaml:"widthInches"`
+	HeightInches float64 `json:"heightInches" yaml:"heightInches"`
+	// Orientation is "L" (landscape) or "P" (portrait).
+	Orientation  string  `json:"orientation" yaml:"orientation"`
+	MarginInches float64 `json:"marginInches" yaml:"marginInches"`
+	Border       bool    `json:"border" yaml:"border"`
+	Zones        []Zone  `json:"zones" yaml:"zones"`
+}
+
+// LoadTemplate reads a Template from a .json, .yaml, or .yml file.
+func LoadTemplate(path string) (Template, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	var tmpl Template
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &tmpl)
+	default:
+		err = json.Unmarshal(raw, &tmpl)
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// LoadTemplateDir loads every .json/.yaml/.yml file in dir as a Template,
+// keyed by Template.Name. A missing directory is treated as "no user
+// templates" rather than an error, since most installs won't have one.
+func LoadTemplateDir(dir string) (map[string]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string]Template{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+
+	templates := map[string]Template{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		tmpl, err := LoadTemplate(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates[tmpl.Name] = tmpl
+	}
+	return templates, nil
+}